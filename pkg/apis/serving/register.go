@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+const (
+	// GroupName is the group name for the Knative Serving API types.
+	GroupName = "serving.knative.dev"
+
+	// PropagateLabelsAnnotationKey is the annotation that, when set on a
+	// Configuration, allowlists label key patterns (comma-separated,
+	// trailing "*" allowed for a prefix match) that are propagated to
+	// Revisions created from it, in addition to whatever the cluster-wide
+	// config-features allowlist already permits.
+	PropagateLabelsAnnotationKey = GroupName + "/propagate-labels"
+
+	// PropagateAnnotationsAnnotationKey is the Configuration-scoped
+	// counterpart of PropagateLabelsAnnotationKey for annotations.
+	PropagateAnnotationsAnnotationKey = GroupName + "/propagate-annotations"
+
+	// ForceRevisionTimestampAnnotationKey is the annotation that callers
+	// (e.g. `kn`) bump to force the Configuration controller to stamp a new
+	// Revision even though spec.template is byte-identical to the previous
+	// one, e.g. to re-pull a `:latest` image or re-read a referenced
+	// ConfigMap or Secret. Only the fact that its value changed matters -
+	// the value itself is opaque to the controller, by convention an
+	// RFC3339 timestamp.
+	ForceRevisionTimestampAnnotationKey = GroupName + "/force-revision-timestamp"
+
+	// PropagatedLabelKeysAnnotationKey is a controller-internal bookkeeping
+	// annotation the Configuration reconciler stamps onto a Revision, a
+	// comma-separated list of the label keys it propagated from the owning
+	// Configuration as of the last reconcile. It lets the reconciler tell
+	// apart "never propagated" from "propagated, then the allowlist or the
+	// Configuration's labels changed so this key should be removed" the next
+	// time it reconciles that Revision's metadata.
+	PropagatedLabelKeysAnnotationKey = GroupName + "/propagated-label-keys"
+
+	// PropagatedAnnotationKeysAnnotationKey is the annotation analogue of
+	// PropagatedLabelKeysAnnotationKey for propagated annotations.
+	PropagatedAnnotationKeysAnnotationKey = GroupName + "/propagated-annotation-keys"
+
+	// LastObservedMetadataAnnotationKey is a controller-internal bookkeeping
+	// annotation the Configuration reconciler stamps onto the Configuration
+	// itself: a JSON snapshot of the labels/annotations (and the Revision
+	// name they were last observed alongside) as of the last reconcile. It
+	// is what Status.MetadataHistory is diffed against, so that diffing
+	// survives a controller restart and needs no unbounded in-process cache.
+	LastObservedMetadataAnnotationKey = GroupName + "/last-observed-metadata"
+)
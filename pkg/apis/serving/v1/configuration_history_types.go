@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxMetadataHistoryEntries is the default cap on the number of entries kept
+// in ConfigurationStatus.MetadataHistory, used whenever the cluster has not
+// set config-features' metadata-history-limit key
+// (config.MetadataHistoryLimitKey) to a different value.
+const MaxMetadataHistoryEntries = 10
+
+// ConfigurationMetadataChange is a single recorded metadata-only mutation of
+// a Configuration: a change to its labels and/or annotations that was
+// observed with no change to spec.template. Such changes do not bump
+// metadata.generation.
+type ConfigurationMetadataChange struct {
+	// Generation is the Configuration's metadata.generation at the time of
+	// this change.
+	Generation int64 `json:"generation"`
+
+	// Timestamp is when the reconciler observed the change.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// ChangedLabels lists the label keys that were added, removed, or
+	// changed in value by this change, relative to the labels the
+	// reconciler last observed on the Configuration.
+	// +optional
+	ChangedLabels []string `json:"changedLabels,omitempty"`
+
+	// ChangedAnnotations is the annotation analogue of ChangedLabels.
+	// +optional
+	ChangedAnnotations []string `json:"changedAnnotations,omitempty"`
+
+	// User is the identity that made the change, taken from the
+	// Configuration's managed fields at the time it was observed.
+	// +optional
+	User string `json:"user,omitempty"`
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "strings"
+
+// PropagatedMetadata returns the subset of `source` whose keys match one of
+// `patterns`. A pattern ending in "*" matches any key sharing that prefix
+// (e.g. "team/*" matches "team/owner"); any other pattern must match the key
+// exactly. It is used to evaluate the per-Configuration
+// `serving.knative.dev/propagate-labels` (or `-annotations`) annotation,
+// where callers are expected to spell out whether they mean a prefix or an
+// exact key.
+func PropagatedMetadata(source map[string]string, patterns []string) map[string]string {
+	if len(source) == 0 || len(patterns) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(source))
+	for k, v := range source {
+		if matchesAny(k, patterns) {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// PropagatedMetadataByPrefix returns the subset of `source` whose keys have
+// one of `prefixes` as a prefix. Unlike PropagatedMetadata, every entry is
+// always treated as a prefix, never an exact-match pattern - it is used to
+// evaluate the cluster-wide config-features allowlists
+// (config.PropagateLabelPrefixesKey, config.PropagateAnnotationPrefixesKey),
+// which are documented and named as plain key prefixes.
+func PropagatedMetadataByPrefix(source map[string]string, prefixes []string) map[string]string {
+	if len(source) == 0 || len(prefixes) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(source))
+	for k, v := range source {
+		for _, p := range prefixes {
+			if p != "" && strings.HasPrefix(k, p) {
+				out[k] = v
+				break
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func matchesAny(key string, patterns []string) bool {
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(key, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+			continue
+		}
+		if key == p {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePropagationAnnotation splits the value of a
+// `serving.knative.dev/propagate-labels` or `-annotations` annotation (a
+// comma-separated pattern list) into its individual patterns.
+func ParsePropagationAnnotation(value string) []string {
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Configuration represents the "floating HEAD" of a linear history of
+// Revisions. Users create new Revisions by updating the Configuration's
+// spec. The "latest created" and "latest ready" revision resulting from
+// that update are, in turn, available in the Configuration's status.
+type Configuration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the Configuration (from the client).
+	// +optional
+	Spec ConfigurationSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the Configuration (from
+	// the controller).
+	// +optional
+	Status ConfigurationStatus `json:"status,omitempty"`
+}
+
+// ConfigurationSpec holds the desired state of the Configuration (from the
+// client).
+type ConfigurationSpec struct {
+	// Template holds the latest specification for the Revision to be
+	// stamped out.
+	Template RevisionTemplateSpec `json:"template"`
+}
+
+// RevisionTemplateSpec describes the data a Revision should have when
+// created from a template.
+type RevisionTemplateSpec struct {
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the Revision.
+	Spec RevisionSpec `json:"spec,omitempty"`
+}
+
+// ConfigurationStatus communicates the observed state of the Configuration
+// (from the controller).
+type ConfigurationStatus struct {
+	// ObservedGeneration is the 'Generation' of the Configuration that was
+	// last processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LatestReadyRevisionName is the name of the latest Revision stamped
+	// out from this Configuration that has had its "Ready" condition become
+	// "True".
+	// +optional
+	LatestReadyRevisionName string `json:"latestReadyRevisionName,omitempty"`
+
+	// LatestCreatedRevisionName is the last revision that was created from
+	// this Configuration's Spec, good, bad, or in between.
+	// +optional
+	LatestCreatedRevisionName string `json:"latestCreatedRevisionName,omitempty"`
+
+	// MetadataHistory is a bounded, most-recent-last log of metadata-only
+	// mutations (label/annotation changes that did not also change
+	// spec.template) the controller has observed on this Configuration.
+	// It is capped at MaxMetadataHistoryEntries entries, or at the value of
+	// the config-features metadata-history-limit key when the cluster sets
+	// one.
+	// +optional
+	MetadataHistory []ConfigurationMetadataChange `json:"metadataHistory,omitempty"`
+}
+
+// RevisionSpec holds the desired state of the Revision (from the client).
+type RevisionSpec struct {
+	// PodSpec holds the desired state of the single pod backing this
+	// Revision.
+	PodSpec corev1.PodSpec `json:"podSpec,omitempty"`
+
+	// ContainerConcurrency specifies the maximum allowed in-flight
+	// (concurrent) requests per container of the Revision.
+	// +optional
+	ContainerConcurrency *int64 `json:"containerConcurrency,omitempty"`
+
+	// TimeoutSeconds is the maximum duration in seconds that the request
+	// routing layer will wait for a request delivered to a container to
+	// begin replying.
+	// +optional
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+}
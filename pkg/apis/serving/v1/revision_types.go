@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Revision is an immutable snapshot of code and configuration. A Revision
+// references a container image and, once created, its spec is never updated
+// again; a new Revision is stamped out from the owning Configuration
+// instead. Only its labels and annotations are reconciled afterwards, from
+// the owning Configuration's propagation allowlist.
+type Revision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the Revision (from the controller).
+	// +optional
+	Spec RevisionSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the Revision (from the
+	// controller).
+	// +optional
+	Status RevisionStatus `json:"status,omitempty"`
+}
+
+// RevisionStatus communicates the observed state of the Revision (from the
+// controller).
+type RevisionStatus struct {
+	// ObservedGeneration is the 'Generation' of the Revision that was last
+	// processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
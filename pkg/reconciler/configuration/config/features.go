@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PropagateLabelPrefixesKey is the config-features key holding a
+	// comma-separated list of label key prefixes that are allowed to
+	// propagate from a Configuration to the Revisions it creates.
+	PropagateLabelPrefixesKey = "propagate-label-prefixes"
+
+	// PropagateAnnotationPrefixesKey is the config-features key holding a
+	// comma-separated list of annotation key prefixes that are allowed to
+	// propagate from a Configuration to the Revisions it creates.
+	PropagateAnnotationPrefixesKey = "propagate-annotation-prefixes"
+
+	// MetadataHistoryLimitKey is the config-features key holding the
+	// cluster-wide cap on the number of entries kept in a Configuration's
+	// Status.MetadataHistory. When unset, zero or negative,
+	// v1.MaxMetadataHistoryEntries is used instead; a value that is not an
+	// integer makes NewFeaturesFromConfigMap return an error.
+	MetadataHistoryLimitKey = "metadata-history-limit"
+)
+
+// Features is the subset of config-features that controls what Configuration
+// metadata is allowed to propagate to child Revisions, and how much
+// metadata-change history Configurations retain.
+type Features struct {
+	// PropagateLabelPrefixes is the cluster-wide allowlist of label key
+	// prefixes that may be copied from a Configuration onto its Revisions.
+	PropagateLabelPrefixes []string
+
+	// PropagateAnnotationPrefixes is the cluster-wide allowlist of
+	// annotation key prefixes that may be copied from a Configuration onto
+	// its Revisions.
+	PropagateAnnotationPrefixes []string
+
+	// MetadataHistoryLimit is the cluster-wide cap on the number of
+	// Status.MetadataHistory entries a Configuration retains. Zero means
+	// the cluster has not overridden the default.
+	MetadataHistoryLimit int
+}
+
+// NewFeaturesFromConfigMap creates a Features from the supplied ConfigMap.
+func NewFeaturesFromConfigMap(cm *corev1.ConfigMap) (*Features, error) {
+	f := &Features{}
+	if v, ok := cm.Data[PropagateLabelPrefixesKey]; ok {
+		f.PropagateLabelPrefixes = splitAndTrim(v)
+	}
+	if v, ok := cm.Data[PropagateAnnotationPrefixesKey]; ok {
+		f.PropagateAnnotationPrefixes = splitAndTrim(v)
+	}
+	if v, ok := cm.Data[MetadataHistoryLimitKey]; ok {
+		limit, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		f.MetadataHistoryLimit = limit
+	}
+	return f, nil
+}
+
+func splitAndTrim(v string) []string {
+	parts := strings.Split(v, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
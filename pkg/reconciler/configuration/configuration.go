@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/reconciler/configuration/config"
+)
+
+// RevisionClient is the subset of the generated Revisions clientset this
+// reconciler needs. It is declared here, rather than depended on directly,
+// so the reconciler stays testable without the full generated clientset.
+type RevisionClient interface {
+	Get(ctx context.Context, name string) (*v1.Revision, error)
+	Create(ctx context.Context, rev *v1.Revision) (*v1.Revision, error)
+	Update(ctx context.Context, rev *v1.Revision) (*v1.Revision, error)
+}
+
+// FeaturesLoader loads the current config-features snapshot, mirroring the
+// configmap.Watcher-backed stores used elsewhere in this reconciler family.
+type FeaturesLoader interface {
+	Load() *config.Features
+}
+
+// Reconciler implements the per-Configuration half of the Configuration
+// controller: given the latest observed Configuration, it stamps out a new
+// Revision when spec.template (or the force-revision-timestamp annotation)
+// calls for one, reconciles allowlisted propagated metadata onto the
+// current Revision either way, and records metadata-only changes onto
+// Status.MetadataHistory.
+type Reconciler struct {
+	RevisionClient RevisionClient
+	Features       FeaturesLoader
+}
+
+// ReconcileKind is called by the generated controller scaffolding with the
+// latest informer-cached copy of a Configuration. It mutates cfg.Status (and,
+// via recordMetadataHistory, cfg.Annotations) in place; the caller is
+// responsible for persisting both.
+func (r *Reconciler) ReconcileKind(ctx context.Context, cfg *v1.Configuration) error {
+	features := r.Features.Load()
+
+	create, name, err := shouldCreateRevision(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to compute revision name for configuration %q: %w", cfg.Name, err)
+	}
+
+	recordMetadataHistory(cfg, name, effectiveMetadataHistoryLimit(features))
+
+	if create {
+		rev, err := r.createRevision(ctx, cfg, name, features)
+		if err != nil {
+			return fmt.Errorf("failed to create revision %q for configuration %q: %w", name, cfg.Name, err)
+		}
+		cfg.Status.LatestCreatedRevisionName = rev.Name
+		return nil
+	}
+
+	return r.reconcilePropagatedMetadata(ctx, cfg, name, features)
+}
+
+// createRevision stamps out a new Revision named `name` from
+// cfg.Spec.Template, applying whatever labels/annotations the propagation
+// policy in `features` allowlists on top of it.
+func (r *Reconciler) createRevision(ctx context.Context, cfg *v1.Configuration, name string, features *config.Features) (*v1.Revision, error) {
+	rev := &v1.Revision{
+		// DeepCopy, not a plain struct copy: cfg.Spec.Template.ObjectMeta's
+		// Labels/Annotations maps are shared with the informer cache, and
+		// applyPropagatedMetadata below mutates the maps it's handed in
+		// place.
+		ObjectMeta: *cfg.Spec.Template.ObjectMeta.DeepCopy(),
+		Spec:       cfg.Spec.Template.Spec,
+	}
+	rev.Name = name
+	rev.Namespace = cfg.Namespace
+	rev.Labels, rev.Annotations = applyPropagatedMetadata(cfg, features, rev.Labels, rev.Annotations)
+
+	created, err := r.RevisionClient.Create(ctx, rev)
+	if apierrors.IsAlreadyExists(err) {
+		// name is a pure content hash of spec.template (plus the
+		// force-revision-timestamp annotation), so reverting spec.template
+		// back to an earlier value - an ordinary user action - reproduces
+		// the name of a Revision that already exists. Treat that as success
+		// rather than wedging the reconcile into a permanent error loop.
+		return r.RevisionClient.Get(ctx, name)
+	}
+	return created, err
+}
+
+// reconcilePropagatedMetadata brings the already-created Revision `name`'s
+// labels/annotations in line with what the propagation policy in `features`
+// currently allowlists, without touching its spec - so that a change to the
+// Configuration's metadata, or to the allowlist itself, converges onto an
+// existing Revision without a new Revision being created or traffic being
+// shifted.
+func (r *Reconciler) reconcilePropagatedMetadata(ctx context.Context, cfg *v1.Configuration, name string, features *config.Features) error {
+	rev, err := r.RevisionClient.Get(ctx, name)
+	if apierrors.IsNotFound(err) {
+		// The Revision hasn't shown up in our view of the world yet; the
+		// next reconcile will retry once it has.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get revision %q: %w", name, err)
+	}
+
+	labels, annotations := applyPropagatedMetadata(cfg, features, copyMap(rev.Labels), copyMap(rev.Annotations))
+	if mapsEqual(labels, rev.Labels) && mapsEqual(annotations, rev.Annotations) {
+		return nil
+	}
+
+	rev.Labels, rev.Annotations = labels, annotations
+	_, err = r.RevisionClient.Update(ctx, rev)
+	return err
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
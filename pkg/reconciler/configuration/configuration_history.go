@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"encoding/json"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/reconciler/configuration/config"
+)
+
+// metadataSnapshot is what recordMetadataHistory persists (JSON-encoded,
+// under serving.LastObservedMetadataAnnotationKey) to diff a Configuration's
+// labels/annotations against what was last observed. revisionName is
+// included so a real spec.template change - a new Revision, not a
+// metadata-only update - can be told apart from a pure label/annotation
+// change without needing metadata.generation, which never bumps for the
+// latter.
+type metadataSnapshot struct {
+	RevisionName string            `json:"revisionName"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// effectiveMetadataHistoryLimit returns the cluster-wide
+// config-features-configured cap on Status.MetadataHistory, falling back to
+// v1.MaxMetadataHistoryEntries when the cluster hasn't set one.
+func effectiveMetadataHistoryLimit(features *config.Features) int {
+	if features != nil && features.MetadataHistoryLimit > 0 {
+		return features.MetadataHistoryLimit
+	}
+	return v1.MaxMetadataHistoryEntries
+}
+
+// recordMetadataHistory appends a ConfigurationMetadataChange to
+// cfg.Status.MetadataHistory when cfg's labels or annotations differ from
+// what was last observed for it, and trims the history to maxEntries. The
+// last-observed snapshot is read from and rewritten to
+// cfg.Annotations[serving.LastObservedMetadataAnnotationKey] rather than
+// kept in an in-process cache, so that diffing survives a controller
+// restart without needing an unbounded per-Configuration map that's never
+// evicted when a Configuration is deleted.
+//
+// Unlike spec.template changes, label/annotation-only changes never bump
+// metadata.generation, so this is keyed purely on the label/annotation diff.
+// currentRevisionName - the name shouldCreateRevision computed for `cfg` -
+// is compared against the name in the snapshot to tell a real spec.template
+// change (a new Revision, already visible via LatestCreatedRevisionName)
+// apart from a metadata-only update; no history entry is produced for the
+// former.
+func recordMetadataHistory(cfg *v1.Configuration, currentRevisionName string, maxEntries int) {
+	observedAnnotations := copyMap(cfg.Annotations)
+	delete(observedAnnotations, serving.LastObservedMetadataAnnotationKey)
+
+	var prev metadataSnapshot
+	hadPrev := false
+	if raw, ok := cfg.Annotations[serving.LastObservedMetadataAnnotationKey]; ok {
+		hadPrev = json.Unmarshal([]byte(raw), &prev) == nil
+	}
+
+	if hadPrev && prev.RevisionName == currentRevisionName {
+		changedLabels := changedKeys(prev.Labels, cfg.Labels)
+		changedAnnotations := changedKeys(prev.Annotations, observedAnnotations)
+		if len(changedLabels) > 0 || len(changedAnnotations) > 0 {
+			entry := v1.ConfigurationMetadataChange{
+				Generation:         cfg.Generation,
+				Timestamp:          metav1.Now(),
+				ChangedLabels:      changedLabels,
+				ChangedAnnotations: changedAnnotations,
+				User:               lastWriter(cfg.ManagedFields),
+			}
+
+			history := append(cfg.Status.MetadataHistory, entry)
+			if len(history) > maxEntries {
+				history = history[len(history)-maxEntries:]
+			}
+			cfg.Status.MetadataHistory = history
+		}
+	}
+
+	raw, err := json.Marshal(metadataSnapshot{
+		RevisionName: currentRevisionName,
+		Labels:       copyMap(cfg.Labels),
+		Annotations:  observedAnnotations,
+	})
+	if err != nil {
+		// Labels/annotations are plain string maps; this can't fail in
+		// practice. Leave the stale snapshot in place rather than lose
+		// today's observation entirely.
+		return
+	}
+	if cfg.Annotations == nil {
+		cfg.Annotations = map[string]string{}
+	}
+	cfg.Annotations[serving.LastObservedMetadataAnnotationKey] = string(raw)
+}
+
+// changedKeys returns, sorted, the keys that were added, removed, or whose
+// value changed between `before` and `after`.
+func changedKeys(before, after map[string]string) []string {
+	seen := map[string]struct{}{}
+	var changed []string
+	for k, v := range after {
+		if bv, ok := before[k]; !ok || bv != v {
+			if _, dup := seen[k]; !dup {
+				changed = append(changed, k)
+				seen[k] = struct{}{}
+			}
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			if _, dup := seen[k]; !dup {
+				changed = append(changed, k)
+				seen[k] = struct{}{}
+			}
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// lastWriter returns the manager of the most recently applied managed
+// field entry, used as the "user" attribution for a metadata history entry.
+func lastWriter(managedFields []metav1.ManagedFieldsEntry) string {
+	var latest *metav1.ManagedFieldsEntry
+	for i := range managedFields {
+		mf := &managedFields[i]
+		if mf.Time == nil {
+			continue
+		}
+		if latest == nil || mf.Time.After(latest.Time.Time) {
+			latest = mf
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Manager
+}
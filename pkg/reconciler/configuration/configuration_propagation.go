@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"sort"
+	"strings"
+
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/reconciler/configuration/config"
+)
+
+// propagatedLabels returns the Configuration labels that are allowed to be
+// copied onto Revisions it creates or reconciles: the cluster-wide
+// config-features prefix allowlist, unioned with whatever the Configuration's
+// own `serving.knative.dev/propagate-labels` annotation additionally allows.
+func propagatedLabels(cfg *v1.Configuration, features *config.Features) map[string]string {
+	return unionMetadata(
+		v1.PropagatedMetadataByPrefix(cfg.Labels, features.PropagateLabelPrefixes),
+		v1.PropagatedMetadata(cfg.Labels, v1.ParsePropagationAnnotation(cfg.Annotations[serving.PropagateLabelsAnnotationKey])),
+	)
+}
+
+// propagatedAnnotations is the annotation analogue of propagatedLabels.
+func propagatedAnnotations(cfg *v1.Configuration, features *config.Features) map[string]string {
+	return unionMetadata(
+		v1.PropagatedMetadataByPrefix(cfg.Annotations, features.PropagateAnnotationPrefixes),
+		v1.PropagatedMetadata(cfg.Annotations, v1.ParsePropagationAnnotation(cfg.Annotations[serving.PropagateAnnotationsAnnotationKey])),
+	)
+}
+
+func unionMetadata(a, b map[string]string) map[string]string {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	out := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		out[k] = v
+	}
+	return out
+}
+
+// applyPropagatedMetadata stamps the Configuration's currently allowlisted
+// labels and annotations onto the Revision labels and annotations it is
+// handed (revLabels, revAnnotations, which it returns), and removes whatever it
+// had previously propagated that's no longer allowlisted - e.g. because the
+// source label/annotation was deleted from the Configuration, or dropped
+// from the allowlist. It is called both when a new Revision is being
+// created from a Configuration and when reconciling an existing Revision,
+// so that changes to the allowlist or to the Configuration's metadata
+// converge onto already-created Revisions in either direction, without
+// requiring a new `spec.template` (and therefore without a new Revision or
+// traffic shift).
+//
+// It tracks what it propagated last time via
+// serving.PropagatedLabelKeysAnnotationKey and
+// serving.PropagatedAnnotationKeysAnnotationKey, two bookkeeping annotations
+// it maintains in revAnnotations itself, since the Revision has no other memory of
+// which of its labels/annotations originated from the Configuration versus
+// were set some other way.
+func applyPropagatedMetadata(cfg *v1.Configuration, features *config.Features, revLabels, revAnnotations map[string]string) (map[string]string, map[string]string) {
+	desiredLabels := propagatedLabels(cfg, features)
+	desiredAnnotations := propagatedAnnotations(cfg, features)
+
+	revLabels = reconcileTrackedKeys(revLabels, desiredLabels, splitTrackedKeys(revAnnotations[serving.PropagatedLabelKeysAnnotationKey]))
+	revAnnotations = reconcileTrackedKeys(revAnnotations, desiredAnnotations, splitTrackedKeys(revAnnotations[serving.PropagatedAnnotationKeysAnnotationKey]))
+
+	revAnnotations = setTrackedKeys(revAnnotations, serving.PropagatedLabelKeysAnnotationKey, desiredLabels)
+	revAnnotations = setTrackedKeys(revAnnotations, serving.PropagatedAnnotationKeysAnnotationKey, desiredAnnotations)
+
+	return revLabels, revAnnotations
+}
+
+// reconcileTrackedKeys removes from `dst` any of `previouslyTracked` that is
+// no longer in `desired`, then applies every key/value in `desired`.
+func reconcileTrackedKeys(dst, desired map[string]string, previouslyTracked []string) map[string]string {
+	for _, k := range previouslyTracked {
+		if _, stillWanted := desired[k]; !stillWanted {
+			delete(dst, k)
+		}
+	}
+	for k, v := range desired {
+		if dst == nil {
+			dst = map[string]string{}
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// setTrackedKeys records the keys of `desired`, sorted and comma-joined,
+// under `annotationKey` in `dst`, so the next reconcile can tell which keys
+// it previously propagated. It clears the annotation entirely rather than
+// leaving it empty when `desired` is empty.
+func setTrackedKeys(dst map[string]string, annotationKey string, desired map[string]string) map[string]string {
+	if len(desired) == 0 {
+		delete(dst, annotationKey)
+		return dst
+	}
+	keys := make([]string, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if dst == nil {
+		dst = map[string]string{}
+	}
+	dst[annotationKey] = strings.Join(keys, ",")
+	return dst
+}
+
+// splitTrackedKeys is the reverse of the joining done by setTrackedKeys.
+func splitTrackedKeys(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// revisionName is the single source of truth for the name of the Revision
+// that `cfg`'s current spec.template calls for. It combines cfg.Spec.Template
+// with the value (if any) of the serving.knative.dev/force-revision-timestamp
+// annotation into the name's suffix, so two generations of the same
+// Configuration with byte-identical spec.template but different annotation
+// values are assigned different names - bumping the annotation is enough to
+// force a new Revision even when nothing else changed, mirroring the
+// client.knative.dev/updateTimestamp pattern `kn` uses to force a re-pull of
+// a `:latest` image or a re-read of a referenced ConfigMap/Secret.
+//
+// ReconcileKind uses revisionName both to decide whether a new Revision
+// needs to be created (it compares the result against
+// cfg.Status.LatestCreatedRevisionName) and as the name to create it under,
+// so there is exactly one computation answering "does spec.template (plus
+// the force-revision-timestamp annotation) call for a new Revision?".
+func revisionName(cfg *v1.Configuration) (string, error) {
+	template, err := json.Marshal(cfg.Spec.Template)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal spec.template: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write(template)
+	h.Write([]byte(cfg.Annotations[serving.ForceRevisionTimestampAnnotationKey]))
+	return fmt.Sprintf("%s-%x", cfg.Name, h.Sum(nil)[:8]), nil
+}
+
+// shouldCreateRevision reports whether ReconcileKind needs to stamp out a
+// new Revision for `cfg`: either spec.template itself changed, or only the
+// force-revision-timestamp annotation did. It returns the name the new (or
+// already-existing) Revision must have.
+func shouldCreateRevision(cfg *v1.Configuration) (create bool, name string, err error) {
+	name, err = revisionName(cfg)
+	if err != nil {
+		return false, "", err
+	}
+	return cfg.Status.LatestCreatedRevisionName != name, name, nil
+}
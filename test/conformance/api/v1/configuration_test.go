@@ -22,10 +22,13 @@ package v1
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/test"
 	v1test "knative.dev/serving/test/v1"
@@ -133,6 +136,20 @@ func TestUpdateConfigurationMetadata(t *testing.T) {
 		t.Errorf("The labels for Revision %s of Configuration %s should not have been updated: %v", names.Revision, names.Config, err)
 	}
 
+	t.Log("Validating a metadata history entry was recorded for the label update", names.Config)
+	labelEntry := v1test.LastMetadataHistoryEntry(cfg)
+	if labelEntry == nil {
+		t.Fatalf("Expected Configuration %s to have a MetadataHistory entry after the label update", names.Config)
+	}
+	if labelEntry.Generation != cfg.Generation {
+		t.Errorf("MetadataHistory entry Generation = %d, want %d", labelEntry.Generation, cfg.Generation)
+	}
+	for k := range newLabels {
+		if !v1test.ContainsChangedKey(labelEntry.ChangedLabels, k) {
+			t.Errorf("Expected MetadataHistory entry for Configuration %s to list %q in ChangedLabels, got %v", names.Config, k, labelEntry.ChangedLabels)
+		}
+	}
+
 	t.Log("Updating annotations of Configuration", names.Config)
 	newAnnotations := map[string]string{
 		"annotation-a": "123",
@@ -163,6 +180,197 @@ func TestUpdateConfigurationMetadata(t *testing.T) {
 	if err != nil {
 		t.Errorf("The annotations for Revision %s of Configuration %s should not have been updated: %v", names.Revision, names.Config, err)
 	}
+
+	t.Log("Validating a metadata history entry was recorded for the annotation update", names.Config)
+	annotationEntry := v1test.LastMetadataHistoryEntry(cfg)
+	if annotationEntry == nil {
+		t.Fatalf("Expected Configuration %s to have a MetadataHistory entry after the annotation update", names.Config)
+	}
+	if annotationEntry.Generation != cfg.Generation {
+		t.Errorf("MetadataHistory entry Generation = %d, want %d", annotationEntry.Generation, cfg.Generation)
+	}
+	for k := range newAnnotations {
+		if !v1test.ContainsChangedKey(annotationEntry.ChangedAnnotations, k) {
+			t.Errorf("Expected MetadataHistory entry for Configuration %s to list %q in ChangedAnnotations, got %v", names.Config, k, annotationEntry.ChangedAnnotations)
+		}
+	}
+	historyLenAfterMetadataOnly := len(cfg.Status.MetadataHistory)
+
+	t.Log("Updating spec.template of Configuration", names.Config)
+	cfg.Spec.Template.Spec.PodSpec.Containers[0].Env = append(cfg.Spec.Template.Spec.PodSpec.Containers[0].Env, corev1.EnvVar{
+		Name:  "FOO",
+		Value: "bar",
+	})
+	if _, err = clients.ServingClient.Configs.Update(context.Background(), cfg, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update spec.template for Configuration %s: %v", names.Config, err)
+	}
+
+	if err = v1test.WaitForConfigurationState(clients.ServingClient, names.Config, func(c *v1.Configuration) (bool, error) {
+		return c.Status.LatestCreatedRevisionName != expected, nil
+	}, "ConfigurationSpecUpdated"); err != nil {
+		t.Fatalf("Did not observe a new Revision after updating spec.template on Configuration %s: %v", names.Config, err)
+	}
+
+	t.Log("Validating the spec.template update did not spuriously add a MetadataHistory entry", names.Config)
+	cfg = fetchConfiguration(names.Config, clients, t)
+	if got := len(cfg.Status.MetadataHistory); got != historyLenAfterMetadataOnly {
+		t.Errorf("len(MetadataHistory) after a spec-only change = %d, want %d (unchanged)", got, historyLenAfterMetadataOnly)
+	}
+}
+
+// TestForceNewRevisionAnnotation verifies that bumping the
+// serving.knative.dev/force-revision-timestamp annotation on a Configuration
+// stamps a new Revision even though spec.template did not change, while
+// other annotation changes continue to leave LatestCreatedRevisionName
+// untouched.
+func TestForceNewRevisionAnnotation(t *testing.T) {
+	if test.ServingFlags.DisableOptionalAPI {
+		t.Skip("Configuration create/patch/replace APIs are not required by Knative Serving API Specification")
+	}
+
+	t.Parallel()
+	clients := test.Setup(t)
+
+	names := test.ResourceNames{
+		Config: test.ObjectNameForTest(t),
+		Image:  test.PizzaPlanet1,
+	}
+
+	test.EnsureTearDown(t, clients, &names)
+
+	t.Log("Creating new configuration", names.Config)
+	if _, err := v1test.CreateConfiguration(t, clients, names); err != nil {
+		t.Fatal("Failed to create configuration", names.Config)
+	}
+
+	if err := v1test.WaitForConfigurationState(clients.ServingClient, names.Config, v1test.IsConfigurationReady, "ConfigurationIsReady"); err != nil {
+		t.Fatalf("Configuration %s did not become ready: %v", names.Config, err)
+	}
+
+	cfg := fetchConfiguration(names.Config, clients, t)
+	firstRevision := cfg.Status.LatestCreatedRevisionName
+
+	t.Log("Updating an unrelated annotation of Configuration", names.Config)
+	cfg.Annotations = kmeta.UnionMaps(cfg.Annotations, map[string]string{"annotation-a": "123"})
+	cfg, err := clients.ServingClient.Configs.Update(context.Background(), cfg, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to update annotations for Configuration %s: %v", names.Config, err)
+	}
+	if err = waitForConfigurationAnnotationsUpdate(clients, names, cfg.Annotations); err != nil {
+		t.Fatalf("The annotations for Configuration %s were not updated: %v", names.Config, err)
+	}
+
+	cfg = fetchConfiguration(names.Config, clients, t)
+	if actual := cfg.Status.LatestCreatedRevisionName; actual != firstRevision {
+		t.Errorf("Did not expect a new Revision after an unrelated annotation change on Configuration %s - expected Revision: %s, actual Revision: %s",
+			names.Config, firstRevision, actual)
+	}
+
+	t.Log("Updating the force-revision-timestamp annotation of Configuration", names.Config)
+	cfg.Annotations = kmeta.UnionMaps(cfg.Annotations, map[string]string{
+		serving.ForceRevisionTimestampAnnotationKey: time.Now().UTC().Format(time.RFC3339),
+	})
+	cfg, err = clients.ServingClient.Configs.Update(context.Background(), cfg, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to update the force-revision-timestamp annotation for Configuration %s: %v", names.Config, err)
+	}
+
+	if err = v1test.WaitForConfigurationState(clients.ServingClient, names.Config, func(c *v1.Configuration) (bool, error) {
+		return c.Status.LatestCreatedRevisionName != firstRevision, nil
+	}, "ConfigurationForcedNewRevision"); err != nil {
+		t.Fatalf("Did not observe a new Revision after forcing one on Configuration %s: %v", names.Config, err)
+	}
+
+	cfg = fetchConfiguration(names.Config, clients, t)
+	if actual := cfg.Status.LatestCreatedRevisionName; actual == firstRevision {
+		t.Errorf("Expected a new Revision after updating the force-revision-timestamp annotation on Configuration %s, but LatestCreatedRevisionName is still %s",
+			names.Config, actual)
+	}
+}
+
+// TestUpdateConfigurationMetadataPropagation is the mirror image of
+// TestUpdateConfigurationMetadata: it opts a Configuration into label and
+// annotation propagation via the serving.knative.dev/propagate-labels and
+// serving.knative.dev/propagate-annotations annotations, and asserts the
+// allowlisted keys (and only those) do reach the Revision - still without a
+// new Revision being created.
+func TestUpdateConfigurationMetadataPropagation(t *testing.T) {
+	if test.ServingFlags.DisableOptionalAPI {
+		t.Skip("Configuration create/patch/replace APIs are not required by Knative Serving API Specification")
+	}
+
+	t.Parallel()
+	clients := test.Setup(t)
+
+	names := test.ResourceNames{
+		Config: test.ObjectNameForTest(t),
+		Image:  test.PizzaPlanet1,
+	}
+
+	test.EnsureTearDown(t, clients, &names)
+
+	t.Log("Creating new configuration", names.Config)
+	if _, err := v1test.CreateConfiguration(t, clients, names, func(cfg *v1.Configuration) {
+		cfg.Annotations = kmeta.UnionMaps(cfg.Annotations, map[string]string{
+			serving.PropagateLabelsAnnotationKey:      "team/*,cost-center",
+			serving.PropagateAnnotationsAnnotationKey: "team/*",
+		})
+	}); err != nil {
+		t.Fatal("Failed to create configuration", names.Config)
+	}
+
+	if err := v1test.WaitForConfigurationState(clients.ServingClient, names.Config, v1test.IsConfigurationReady, "ConfigurationIsReady"); err != nil {
+		t.Fatalf("Configuration %s did not become ready: %v", names.Config, err)
+	}
+
+	cfg := fetchConfiguration(names.Config, clients, t)
+	names.Revision = cfg.Status.LatestReadyRevisionName
+
+	t.Log("Updating labels and annotations of Configuration", names.Config)
+	propagatedLabels := map[string]string{
+		"team/owner":  "serving",
+		"cost-center": "1234",
+	}
+	ignoredLabels := map[string]string{
+		"label-x": "abc",
+	}
+	propagatedAnnotations := map[string]string{
+		"team/contact": "serving-oncall",
+	}
+	ignoredAnnotations := map[string]string{
+		"annotation-a": "123",
+	}
+	cfg.Labels = kmeta.UnionMaps(cfg.Labels, propagatedLabels, ignoredLabels)
+	cfg.Annotations = kmeta.UnionMaps(cfg.Annotations, propagatedAnnotations, ignoredAnnotations)
+	cfg, err := clients.ServingClient.Configs.Update(context.Background(), cfg, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to update metadata for Configuration %s: %v", names.Config, err)
+	}
+
+	if err = waitForConfigurationLabelsUpdate(clients, names, cfg.Labels); err != nil {
+		t.Fatalf("The labels for Configuration %s were not updated: %v", names.Config, err)
+	}
+	if err = waitForConfigurationAnnotationsUpdate(clients, names, cfg.Annotations); err != nil {
+		t.Fatalf("The annotations for Configuration %s were not updated: %v", names.Config, err)
+	}
+
+	cfg = fetchConfiguration(names.Config, clients, t)
+	expected, actual := names.Revision, cfg.Status.LatestCreatedRevisionName
+	if expected != actual {
+		t.Errorf("Did not expect a new Revision after updating metadata for Configuration %s - expected Revision: %s, actual Revision: %s",
+			names.Config, expected, actual)
+	}
+
+	t.Log("Validating allowlisted labels and annotations were propagated to Revision", names.Revision)
+	err = v1test.CheckRevisionState(clients.ServingClient, names.Revision, func(r *v1.Revision) (bool, error) {
+		return checkKeysPresent(propagatedLabels, r.Labels, t) &&
+			checkNoKeysPresent(ignoredLabels, r.Labels, t) &&
+			checkKeysPresent(propagatedAnnotations, r.Annotations, t) &&
+			checkNoKeysPresent(ignoredAnnotations, r.Annotations, t), nil
+	})
+	if err != nil {
+		t.Errorf("The propagated metadata for Revision %s of Configuration %s was not as expected: %v", names.Revision, names.Config, err)
+	}
 }
 
 func fetchConfiguration(name string, clients *test.Clients, t *testing.T) *v1.Configuration {
@@ -200,3 +408,19 @@ func checkNoKeysPresent(expected, actual map[string]string, t *testing.T) bool {
 	}
 	return len(present) == 0
 }
+
+// checkKeysPresent returns true if every key/value pair in `expected` is
+// present in `actual`. checkKeysPresent will log the missing keys to t.Log.
+func checkKeysPresent(expected, actual map[string]string, t *testing.T) bool {
+	t.Helper()
+	missing := []string{}
+	for k, v := range expected {
+		if got, ok := actual[k]; !ok || got != v {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) != 0 {
+		t.Log("Missing or mismatched keys:", missing)
+	}
+	return len(missing) == 0
+}
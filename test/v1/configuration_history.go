@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// LastMetadataHistoryEntry returns the most recently recorded entry in
+// cfg.Status.MetadataHistory, or nil if the history is empty. It is meant
+// for e2e assertions that want to check the most recent metadata mutation
+// without reaching into the slice directly.
+func LastMetadataHistoryEntry(cfg *v1.Configuration) *v1.ConfigurationMetadataChange {
+	history := cfg.Status.MetadataHistory
+	if len(history) == 0 {
+		return nil
+	}
+	return &history[len(history)-1]
+}
+
+// ContainsChangedKey reports whether `key` appears in a
+// ConfigurationMetadataChange's ChangedLabels or ChangedAnnotations,
+// whichever `changed` is passed.
+func ContainsChangedKey(changed []string, key string) bool {
+	for _, k := range changed {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}